@@ -1,12 +1,19 @@
 package cview
 
 import (
+	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
+	"unicode"
 
 	"github.com/gdamore/tcell"
 )
 
+// doubleClickInterval is the maximum gap between two clicks at the same
+// position on a window's title bar for them to count as a double-click.
+const doubleClickInterval = 500 * time.Millisecond
+
 type WindowEdge int16
 
 // Available mouse actions.
@@ -33,6 +40,103 @@ const WindowZBottom = 0
 const minWindowWidth = 3
 const minWindowHeight = 3
 
+// snapEdgeThreshold is the distance, in cells, within which a dragged
+// window's title bar triggers a snap preview against a manager edge.
+const snapEdgeThreshold = 2
+
+// SnapRegion identifies a region of the window manager that a window can be
+// snapped to, via WindowManager.SnapWindow or a drag-to-edge gesture.
+type SnapRegion int16
+
+const (
+	SnapNone SnapRegion = iota
+	SnapLeft
+	SnapRight
+	SnapTop
+	SnapBottom
+	SnapTopLeft
+	SnapTopRight
+	SnapBottomLeft
+	SnapBottomRight
+	SnapFull
+)
+
+// Layout selects how a WindowManager arranges its windows.
+type Layout int16
+
+const (
+	// LayoutFloating leaves windows at whatever rect they were given
+	// (subject to snapping). This is the default.
+	LayoutFloating Layout = iota
+
+	// LayoutTiled auto-arranges all non-floating windows into a
+	// binary-space-partition grid, splitting the largest tile for each
+	// newly tiled window.
+	LayoutTiled
+)
+
+// snapRegionRect returns the rect a window should occupy when snapped to
+// region, given the window manager's inner rect.
+func snapRegionRect(region SnapRegion, mx, my, mw, mh int) (x, y, width, height int) {
+	switch region {
+	case SnapLeft:
+		return mx, my, mw / 2, mh
+	case SnapRight:
+		return mx + mw - mw/2, my, mw / 2, mh
+	case SnapTop:
+		return mx, my, mw, mh / 2
+	case SnapBottom:
+		return mx, my + mh - mh/2, mw, mh / 2
+	case SnapTopLeft:
+		return mx, my, mw / 2, mh / 2
+	case SnapTopRight:
+		return mx + mw - mw/2, my, mw / 2, mh / 2
+	case SnapBottomLeft:
+		return mx, my + mh - mh/2, mw / 2, mh / 2
+	case SnapBottomRight:
+		return mx + mw - mw/2, my + mh - mh/2, mw / 2, mh / 2
+	case SnapFull:
+		return mx, my, mw, mh
+	default:
+		return mx, my, mw, mh
+	}
+}
+
+// tileNode is one node of a binary-space-partition tree used by
+// LayoutTiled. A leaf holds a window; an interior node splits its rect
+// between first and second along horizontal or vertical axis.
+type tileNode struct {
+	window     *Window
+	horizontal bool
+	ratio      float64
+	first      *tileNode
+	second     *tileNode
+}
+
+// TileNode is an exported snapshot of a tileNode, returned by
+// WindowManager.Tiles so callers can serialize or inspect the current tiled
+// layout.
+type TileNode struct {
+	Window     *Window
+	Horizontal bool
+	Ratio      float64
+	First      *TileNode
+	Second     *TileNode
+}
+
+func exportTile(n *tileNode) *TileNode {
+	if n == nil {
+		return nil
+	}
+	return &TileNode{
+		Window:     n.window,
+		Horizontal: n.horizontal,
+		Ratio:      n.ratio,
+		First:      exportTile(n.first),
+		Second:     exportTile(n.second),
+	}
+}
+
 type WindowButton struct {
 	Symbol       rune
 	offsetX      int
@@ -54,6 +158,28 @@ type Window struct {
 	maximized     bool
 	Draggable     bool
 	Resizable     bool
+
+	floating   bool
+	snapRegion SnapRegion
+
+	fullscreen         bool
+	fullscreenRestoreX int
+	fullscreenRestoreY int
+	fullscreenRestoreW int
+	fullscreenRestoreH int
+	visible            bool
+	title              string
+	id                 string
+
+	shaded      bool
+	shadeHeight int
+	minimized   bool
+
+	lastClickTime          time.Time
+	lastClickX, lastClickY int
+
+	inputCapture func(event *tcell.EventKey) *tcell.EventKey
+	mouseCapture func(action MouseAction, event *tcell.EventMouse) (*tcell.EventMouse, MouseAction)
 }
 
 // NewWindow creates a new window in this window manager
@@ -64,6 +190,8 @@ func NewWindow() *Window {
 	window.restoreX, window.restoreY, window.restoreHeight, window.restoreWidth = window.GetRect()
 	window.SetBorder(true)
 	window.focus = window
+	window.floating = true
+	window.visible = true
 	return window
 }
 
@@ -91,6 +219,15 @@ func (w *Window) Draw(screen tcell.Screen) {
 	if w.border {
 		x, y, width, height := w.GetRect()
 		screen = NewClipRegion(screen, x, y, width, height)
+
+		if w.title != "" {
+			leftWidth, rightWidth := w.buttonGroupWidths()
+			titleWidth := width - leftWidth - rightWidth
+			if titleWidth > 0 {
+				Print(screen, Escape(truncateEllipsis(w.title, titleWidth)), x+leftWidth, y, titleWidth, AlignCenter, tcell.ColorWhite)
+			}
+		}
+
 		for _, button := range w.buttons {
 			buttonX, buttonY := button.offsetX+x, button.offsetY+y
 			if button.offsetX < 0 {
@@ -112,6 +249,39 @@ func (w *Window) checkManager() {
 	}
 }
 
+// buttonGroupWidths returns the number of cells occupied by the left- and
+// right-aligned button groups on the title bar. A non-empty group also
+// counts the border corner cell on its side; an empty group counts as 0.
+func (w *Window) buttonGroupWidths() (left, right int) {
+	for _, button := range w.buttons {
+		if button.Alignment == AlignRight {
+			right += 3
+		} else {
+			left += 3
+		}
+	}
+	if left > 0 {
+		left++
+	}
+	if right > 0 {
+		right++
+	}
+	return
+}
+
+// truncateEllipsis shortens s to fit within width cells, replacing the end
+// with an ellipsis if it doesn't fit.
+func truncateEllipsis(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	if width <= 1 {
+		return "…"
+	}
+	return string(r[:width-1]) + "…"
+}
+
 func (w *Window) Show() *Window {
 	w.checkManager()
 	w.manager.Show(w)
@@ -129,12 +299,51 @@ func (w *Window) Maximize() *Window {
 	w.restoreX, w.restoreY, w.restoreHeight, w.restoreWidth = w.GetRect()
 	w.SetRect(w.manager.GetInnerRect())
 	w.maximized = true
+	w.manager.fireChanged()
 	return w
 }
 
 func (w *Window) Restore() *Window {
 	w.SetRect(w.restoreX, w.restoreY, w.restoreHeight, w.restoreWidth)
 	w.maximized = false
+	if !w.floating && w.manager != nil {
+		w.manager.Lock()
+		w.manager.removeTile(w)
+		w.manager.Unlock()
+	}
+	w.floating = true
+	w.snapRegion = SnapNone
+	if w.manager != nil {
+		w.manager.fireChanged()
+	}
+	return w
+}
+
+// IsFloating returns whether this window is positioned freely rather than
+// snapped or tiled.
+func (w *Window) IsFloating() bool {
+	return w.floating
+}
+
+// SnapRegion returns the region this window is currently snapped to, or
+// SnapNone if it is floating or tiled.
+func (w *Window) SnapRegion() SnapRegion {
+	return w.snapRegion
+}
+
+// ToggleFloating switches the window between its floating geometry and the
+// manager's tiled layout. Toggling out of floating inserts the window into
+// the tile tree, splitting the largest tile; toggling back calls Restore.
+func (w *Window) ToggleFloating() *Window {
+	w.checkManager()
+	if w.floating {
+		w.manager.Lock()
+		w.manager.insertTile(w)
+		w.manager.Unlock()
+		w.manager.fireChanged()
+	} else {
+		w.Restore()
+	}
 	return w
 }
 
@@ -175,6 +384,143 @@ func (w *Window) IsMaximized() bool {
 	return w.maximized
 }
 
+// SetFullscreen toggles fullscreen mode, distinct from Maximize/Restore:
+// while fullscreen, the window is drawn over the manager's entire inner
+// rect, ignoring its border, and every other window is hidden from the
+// draw loop.
+func (w *Window) SetFullscreen(fullscreen bool) *Window {
+	w.checkManager()
+	if fullscreen == w.fullscreen {
+		return w
+	}
+	if fullscreen {
+		w.fullscreenRestoreX, w.fullscreenRestoreY, w.fullscreenRestoreW, w.fullscreenRestoreH = w.GetRect()
+	} else {
+		w.SetRect(w.fullscreenRestoreX, w.fullscreenRestoreY, w.fullscreenRestoreW, w.fullscreenRestoreH)
+	}
+	w.fullscreen = fullscreen
+	w.manager.fireChanged()
+	return w
+}
+
+// IsFullscreen returns whether this window is in fullscreen mode.
+func (w *Window) IsFullscreen() bool {
+	return w.fullscreen
+}
+
+// drawFullscreen draws the window's root primitive over the given rect,
+// bypassing the border and button chrome.
+func (w *Window) drawFullscreen(screen tcell.Screen, x, y, width, height int) {
+	w.SetRect(x, y, width, height)
+	if w.root == nil {
+		return
+	}
+	w.root.SetRect(x, y, width, height)
+	w.root.Draw(NewClipRegion(screen, x, y, width, height))
+}
+
+// SetVisible shows or hides the window without removing it from its
+// manager. Invisible windows are skipped during drawing, mouse dispatch,
+// and keyboard focus cycling.
+func (w *Window) SetVisible(visible bool) *Window {
+	w.visible = visible
+	if w.manager != nil {
+		w.manager.fireChanged()
+	}
+	return w
+}
+
+// GetVisible returns whether the window is currently visible.
+func (w *Window) GetVisible() bool {
+	return w.visible
+}
+
+// Shade collapses the window to just its title-bar row, useful when many
+// small dialogs clutter the screen. Unshade restores its prior height.
+func (w *Window) Shade() *Window {
+	if w.shaded {
+		return w
+	}
+	x, y, width, height := w.GetRect()
+	w.shadeHeight = height
+	w.SetRect(x, y, width, 1)
+	w.shaded = true
+	if w.manager != nil {
+		w.manager.fireChanged()
+	}
+	return w
+}
+
+// Unshade restores a window collapsed by Shade to its prior height.
+func (w *Window) Unshade() *Window {
+	if !w.shaded {
+		return w
+	}
+	x, y, width, _ := w.GetRect()
+	height := w.shadeHeight
+	if height < minWindowHeight {
+		height = minWindowHeight
+	}
+	w.SetRect(x, y, width, height)
+	w.shaded = false
+	if w.manager != nil {
+		w.manager.fireChanged()
+	}
+	return w
+}
+
+// IsShaded returns whether the window is currently shaded.
+func (w *Window) IsShaded() bool {
+	return w.shaded
+}
+
+// Minimize hides the window from the drawing area while keeping a
+// clickable entry for it in the manager's taskbar, if one is attached via
+// WindowManager.SetTaskbar.
+func (w *Window) Minimize() *Window {
+	w.minimized = true
+	w.visible = false
+	if w.manager != nil {
+		w.manager.fireChanged()
+	}
+	return w
+}
+
+// IsMinimized returns whether the window is currently minimized.
+func (w *Window) IsMinimized() bool {
+	return w.minimized
+}
+
+// SetTitle sets the text drawn centered in the window's top border, between
+// its left- and right-aligned button groups.
+func (w *Window) SetTitle(title string) *Window {
+	w.title = title
+	return w
+}
+
+// GetTitle returns the window's title.
+func (w *Window) GetTitle() string {
+	return w.title
+}
+
+// SetID assigns a caller-chosen identifier to the window, used to resolve
+// it when restoring a saved layout. See WindowManager.SaveLayout.
+func (w *Window) SetID(id string) *Window {
+	w.id = id
+	return w
+}
+
+// GetID returns the window's ID, or the empty string if none was set.
+func (w *Window) GetID() string {
+	return w.id
+}
+
+// restoreRect returns the window's last floated geometry, in the same
+// (x, y, width, height) order SetRect expects.
+func (w *Window) restoreRect() (x, y, width, height int) {
+	return w.restoreX, w.restoreY, w.restoreHeight, w.restoreWidth
+}
+
 // HasFocus returns whether or not this primitive has focus.
 func (w *Window) HasFocus() bool {
 	if w.root != nil {
@@ -186,6 +532,13 @@ func (w *Window) HasFocus() bool {
 
 func (w *Window) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
 	return w.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if w.mouseCapture != nil {
+			event, action = w.mouseCapture(action, event)
+			if event == nil {
+				return true, nil
+			}
+		}
+
 		if action == MouseLeftClick {
 			x, y := event.Position()
 			wx, wy, width, _ := w.GetRect()
@@ -198,6 +551,26 @@ func (w *Window) MouseHandler() func(action MouseAction, event *tcell.EventMouse
 						return true, nil
 					}
 				}
+
+				now := time.Now()
+				doubleClick := x == w.lastClickX && y == w.lastClickY && now.Sub(w.lastClickTime) <= doubleClickInterval
+				w.lastClickTime, w.lastClickX, w.lastClickY = now, x, y
+				if doubleClick {
+					w.lastClickTime = time.Time{}
+					if x >= wx+width/2 {
+						// Right half of the title bar shades/unshades.
+						if w.shaded {
+							w.Unshade()
+						} else {
+							w.Shade()
+						}
+					} else if w.maximized {
+						w.Restore()
+					} else {
+						w.Maximize()
+					}
+					return true, nil
+				}
 			}
 		}
 		if w.root != nil {
@@ -207,6 +580,56 @@ func (w *Window) MouseHandler() func(action MouseAction, event *tcell.EventMouse
 	})
 }
 
+// SetInputCapture installs a function which captures key events before they
+// are forwarded to this window's root primitive, mirroring tview's
+// per-primitive capture propagation. If the captured function returns nil,
+// the event is not forwarded any further.
+func (w *Window) SetInputCapture(capture func(event *tcell.EventKey) *tcell.EventKey) *Window {
+	w.inputCapture = capture
+	return w
+}
+
+// GetInputCapture returns the function installed with SetInputCapture, or
+// nil if no such function has been installed.
+func (w *Window) GetInputCapture() func(event *tcell.EventKey) *tcell.EventKey {
+	return w.inputCapture
+}
+
+// SetMouseCapture installs a function which runs before the window's root
+// primitive receives a mouse event. Returning a nil event cancels
+// propagation entirely: the event is considered consumed and the root
+// never sees it.
+func (w *Window) SetMouseCapture(capture func(action MouseAction, event *tcell.EventMouse) (*tcell.EventMouse, MouseAction)) *Window {
+	w.mouseCapture = capture
+	return w
+}
+
+// GetMouseCapture returns the function installed with SetMouseCapture, or
+// nil if no such function has been installed.
+func (w *Window) GetMouseCapture() func(action MouseAction, event *tcell.EventMouse) (*tcell.EventMouse, MouseAction) {
+	return w.mouseCapture
+}
+
+// InputHandler returns the handler for this primitive. It applies the input
+// capture, if any, and then forwards the event to the window's root
+// primitive.
+func (w *Window) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return w.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		if w.inputCapture != nil {
+			event = w.inputCapture(event)
+			if event == nil {
+				return
+			}
+		}
+		if w.root == nil {
+			return
+		}
+		if handler := w.root.InputHandler(); handler != nil {
+			handler(event, setFocus)
+		}
+	})
+}
+
 func (w *Window) AddButton(button *WindowButton) *Window {
 	w.buttons = append(w.buttons, button)
 
@@ -235,6 +658,138 @@ func (w *Window) ButtonCount() int {
 	return len(w.buttons)
 }
 
+// windowMode tracks the keyboard-driven move/resize modes entered via
+// WindowManager.InputHandler.
+type windowMode int
+
+const (
+	windowModeNormal windowMode = iota
+	windowModeMove
+	windowModeResize
+)
+
+// taskbarHeight is the number of rows a WindowTaskbar occupies.
+const taskbarHeight = 1
+
+// TaskbarEdge identifies which edge of the manager a WindowTaskbar docks to.
+type TaskbarEdge int16
+
+const (
+	TaskbarBottom TaskbarEdge = iota
+	TaskbarTop
+)
+
+// taskbarEntry is one clickable, laid-out slot in a WindowTaskbar.
+type taskbarEntry struct {
+	window *Window
+	x      int
+	width  int
+}
+
+// WindowTaskbar is a primitive that renders one clickable entry per
+// minimized window in a WindowManager, similar to a desktop's taskbar.
+// Clicking an entry restores and focuses its window.
+type WindowTaskbar struct {
+	*Box
+	manager *WindowManager
+	edge    TaskbarEdge
+}
+
+// NewWindowTaskbar creates a new taskbar, docked to the bottom edge by
+// default.
+func NewWindowTaskbar() *WindowTaskbar {
+	return &WindowTaskbar{
+		Box: NewBox().SetBackgroundColor(tcell.ColorDefault),
+	}
+}
+
+// SetEdge sets which edge of the manager the taskbar docks to.
+func (t *WindowTaskbar) SetEdge(edge TaskbarEdge) *WindowTaskbar {
+	t.edge = edge
+	return t
+}
+
+// minimizedWindows returns the manager's minimized windows, in z-order.
+func (t *WindowTaskbar) minimizedWindows() []*Window {
+	if t.manager == nil {
+		return nil
+	}
+	t.manager.Lock()
+	defer t.manager.Unlock()
+	var windows []*Window
+	for _, window := range t.manager.windows {
+		if window.minimized {
+			windows = append(windows, window)
+		}
+	}
+	return windows
+}
+
+// layout lays out one entry per minimized window, left to right, stopping
+// at the first entry that doesn't fit; it and all later entries are dropped.
+func (t *WindowTaskbar) layout() []taskbarEntry {
+	_, _, width, _ := t.GetRect()
+	var entries []taskbarEntry
+	x := 0
+	for _, window := range t.minimizedWindows() {
+		label := window.title
+		if label == "" {
+			label = "window"
+		}
+		entryWidth := len([]rune(label)) + 4
+		if x+entryWidth > width {
+			break
+		}
+		entries = append(entries, taskbarEntry{window: window, x: x, width: entryWidth})
+		x += entryWidth + 1
+	}
+	return entries
+}
+
+// Draw draws this primitive onto the screen.
+func (t *WindowTaskbar) Draw(screen tcell.Screen) {
+	t.Box.Draw(screen)
+	tx, ty, _, _ := t.GetRect()
+	for _, entry := range t.layout() {
+		indicator := ' '
+		if entry.window.HasFocus() {
+			indicator = '*'
+		}
+		label := fmt.Sprintf("[%c] %s", indicator, entry.window.title)
+		Print(screen, Escape(label), tx+entry.x, ty, entry.width, AlignLeft, tcell.ColorWhite)
+	}
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (t *WindowTaskbar) MouseHandler() func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+	return t.WrapMouseHandler(func(action MouseAction, event *tcell.EventMouse, setFocus func(p Primitive)) (consumed bool, capture Primitive) {
+		if action != MouseLeftClick || !t.InRect(event.Position()) {
+			return false, nil
+		}
+
+		tx, _, _, _ := t.GetRect()
+		x, _ := event.Position()
+		for _, entry := range t.layout() {
+			if x < tx+entry.x || x >= tx+entry.x+entry.width {
+				continue
+			}
+
+			window := entry.window
+			window.minimized = false
+			window.visible = true
+			if t.manager != nil {
+				t.manager.Lock()
+				t.manager.setZ(window, WindowZTop)
+				t.manager.Unlock()
+				t.manager.fireChanged()
+			}
+			setFocus(window)
+			return true, nil
+		}
+		return false, nil
+	})
+}
+
 type WindowManager struct {
 	*Box
 
@@ -246,6 +801,29 @@ type WindowManager struct {
 	draggedWindow            *Window
 	draggedEdge              WindowEdge
 	modalWindow              *Window
+
+	// Keyboard navigation: focus switching and move/resize accelerators.
+	switchForwardKey  tcell.Key
+	switchForwardMods tcell.ModMask
+	switchBackKey     tcell.Key
+	switchBackMods    tcell.ModMask
+	moveKey           tcell.Key
+	resizeKey         tcell.Key
+
+	mode       windowMode
+	modeWindow *Window
+
+	// Tiling/snap layout.
+	layout      Layout
+	tiles       *tileNode
+	snapPreview SnapRegion
+
+	changed func()
+
+	mouseCapture func(action MouseAction, event *tcell.EventMouse) (*tcell.EventMouse, MouseAction)
+
+	taskbar *WindowTaskbar
+
 	sync.Mutex
 }
 
@@ -264,9 +842,218 @@ func NewWindowManager() *WindowManager {
 		Box: NewBox().SetBackgroundColor(tcell.ColorDefault),
 	}
 	wm.focus = wm
+
+	wm.switchForwardKey, wm.switchForwardMods = tcell.KeyTAB, tcell.ModCtrl
+	wm.switchBackKey, wm.switchBackMods = tcell.KeyTAB, tcell.ModCtrl|tcell.ModShift
+	wm.moveKey = tcell.KeyF7
+	wm.resizeKey = tcell.KeyF8
+
+	return wm
+}
+
+// SetFocusSwitchAccelerator sets the key combination which cycles focus
+// forward through the manager's visible, non-modal windows. The default is
+// Ctrl+Tab.
+func (wm *WindowManager) SetFocusSwitchAccelerator(key tcell.Key, mods tcell.ModMask) *WindowManager {
+	wm.Lock()
+	defer wm.Unlock()
+	wm.switchForwardKey, wm.switchForwardMods = key, mods
+	return wm
+}
+
+// SetFocusSwitchBackAccelerator sets the key combination which cycles focus
+// backward through the manager's visible, non-modal windows. The default is
+// Ctrl+Shift+Tab.
+func (wm *WindowManager) SetFocusSwitchBackAccelerator(key tcell.Key, mods tcell.ModMask) *WindowManager {
+	wm.Lock()
+	defer wm.Unlock()
+	wm.switchBackKey, wm.switchBackMods = key, mods
+	return wm
+}
+
+// SetMoveAccelerator sets the key which puts the focused window into move
+// mode, where the arrow keys reposition it and Escape or Enter commits the
+// new position. The default is F7.
+func (wm *WindowManager) SetMoveAccelerator(key tcell.Key) *WindowManager {
+	wm.Lock()
+	defer wm.Unlock()
+	wm.moveKey = key
+	return wm
+}
+
+// SetResizeAccelerator sets the key which puts the focused window into
+// resize mode, where the arrow keys adjust its bottom-right edge and Escape
+// or Enter commits the new size. The default is F8.
+func (wm *WindowManager) SetResizeAccelerator(key tcell.Key) *WindowManager {
+	wm.Lock()
+	defer wm.Unlock()
+	wm.resizeKey = key
 	return wm
 }
 
+// focusCycleWindows returns the windows eligible for keyboard focus
+// switching. When a modal window is shown, the switcher is restricted to it.
+func (wm *WindowManager) focusCycleWindows() []*Window {
+	if wm.modalWindow != nil {
+		if !wm.modalWindow.visible {
+			return nil
+		}
+		return []*Window{wm.modalWindow}
+	}
+	windows := make([]*Window, 0, len(wm.windows))
+	for _, window := range wm.windows {
+		if window.visible {
+			windows = append(windows, window)
+		}
+	}
+	return windows
+}
+
+// focusedWindow returns the window which currently has focus, or nil.
+func (wm *WindowManager) focusedWindow() *Window {
+	for _, window := range wm.windows {
+		if window.visible && window.HasFocus() {
+			return window
+		}
+	}
+	return nil
+}
+
+// cycleFocus moves focus to the next (or, if forward is false, the
+// previous) window eligible for keyboard focus switching.
+func (wm *WindowManager) cycleFocus(forward bool, setFocus func(p Primitive)) {
+	wm.Lock()
+	windows := wm.focusCycleWindows()
+	if len(windows) == 0 {
+		wm.Unlock()
+		return
+	}
+
+	current := -1
+	for i, window := range windows {
+		if window.HasFocus() {
+			current = i
+			break
+		}
+	}
+
+	var next int
+	switch {
+	case current == -1 && forward:
+		next = 0
+	case current == -1:
+		next = len(windows) - 1
+	case forward:
+		next = (current + 1) % len(windows)
+	default:
+		next = (current - 1 + len(windows)) % len(windows)
+	}
+	window := windows[next]
+	wm.Unlock()
+
+	setFocus(window)
+}
+
+// InputHandler returns the key handler for this primitive. It cycles focus
+// between windows, drives the keyboard move/resize modes, dispatches window
+// button accelerators (Alt+letter matching a WindowButton's Symbol), and
+// otherwise forwards the event to the focused window.
+func (wm *WindowManager) InputHandler() func(event *tcell.EventKey, setFocus func(p Primitive)) {
+	return wm.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p Primitive)) {
+		wm.Lock()
+
+		if wm.mode != windowModeNormal {
+			window := wm.modeWindow
+			key := event.Key()
+			if key == tcell.KeyEscape || key == tcell.KeyEnter {
+				wm.mode = windowModeNormal
+				wm.modeWindow = nil
+				wm.Unlock()
+				wm.fireChanged()
+				return
+			}
+			if window != nil {
+				switch key {
+				case tcell.KeyUp, tcell.KeyDown, tcell.KeyLeft, tcell.KeyRight:
+					x, y, width, height := window.GetRect()
+					switch wm.mode {
+					case windowModeMove:
+						switch key {
+						case tcell.KeyUp:
+							y--
+						case tcell.KeyDown:
+							y++
+						case tcell.KeyLeft:
+							x--
+						case tcell.KeyRight:
+							x++
+						}
+					case windowModeResize:
+						switch key {
+						case tcell.KeyUp:
+							height--
+						case tcell.KeyDown:
+							height++
+						case tcell.KeyLeft:
+							width--
+						case tcell.KeyRight:
+							width++
+						}
+					}
+					window.SetRect(x, y, width, height)
+				}
+			}
+			wm.Unlock()
+			return
+		}
+
+		if event.Key() == wm.switchBackKey && event.Modifiers() == wm.switchBackMods {
+			wm.Unlock()
+			wm.cycleFocus(false, setFocus)
+			return
+		}
+		if event.Key() == wm.switchForwardKey && event.Modifiers() == wm.switchForwardMods {
+			wm.Unlock()
+			wm.cycleFocus(true, setFocus)
+			return
+		}
+
+		focused := wm.focusedWindow()
+
+		if focused != nil && event.Key() == wm.moveKey {
+			wm.mode = windowModeMove
+			wm.modeWindow = focused
+			wm.Unlock()
+			return
+		}
+		if focused != nil && event.Key() == wm.resizeKey {
+			wm.mode = windowModeResize
+			wm.modeWindow = focused
+			wm.Unlock()
+			return
+		}
+
+		if focused != nil && event.Modifiers()&tcell.ModAlt != 0 {
+			r := event.Rune()
+			for _, button := range focused.buttons {
+				if button.ClickHandler != nil && unicode.ToLower(button.Symbol) == unicode.ToLower(r) {
+					wm.Unlock()
+					button.ClickHandler()
+					return
+				}
+			}
+		}
+
+		wm.Unlock()
+
+		if focused != nil {
+			if handler := focused.InputHandler(); handler != nil {
+				handler(event, setFocus)
+			}
+		}
+	})
+}
+
 // NewWindow creates a new window in this window manager
 func (wm *WindowManager) NewWindow() *Window {
 	window := NewWindow()
@@ -274,16 +1061,25 @@ func (wm *WindowManager) NewWindow() *Window {
 	return window
 }
 
+// Show adds window to this manager, displaying it on the next Draw. If the
+// manager is in LayoutTiled, a newly shown floating window is inserted into
+// the tile tree (splitting the largest tile) rather than left floating on
+// top of the tiles.
 func (wm *WindowManager) Show(window *Window) *WindowManager {
 	wm.Lock()
-	defer wm.Unlock()
 	for _, wnd := range wm.windows {
 		if wnd == window {
+			wm.Unlock()
 			return wm
 		}
 	}
 	window.manager = wm
 	wm.windows = append(wm.windows, window)
+	if wm.layout == LayoutTiled && window.floating {
+		wm.insertTile(window)
+	}
+	wm.Unlock()
+	wm.fireChanged()
 	return wm
 }
 
@@ -297,7 +1093,6 @@ func (wm *WindowManager) ShowModal(window *Window) *WindowManager {
 
 func (wm *WindowManager) Hide(window *Window) *WindowManager {
 	wm.Lock()
-	defer wm.Unlock()
 	if window == wm.modalWindow {
 		wm.modalWindow = nil
 	}
@@ -307,6 +1102,8 @@ func (wm *WindowManager) Hide(window *Window) *WindowManager {
 			break
 		}
 	}
+	wm.Unlock()
+	wm.fireChanged()
 	return wm
 }
 
@@ -380,65 +1177,456 @@ func (wm *WindowManager) setZ(window *Window, newZ int) {
 
 func (wm *WindowManager) SetZ(window *Window, newZ int) *WindowManager {
 	wm.Lock()
-	defer wm.Unlock()
 	wm.setZ(window, newZ)
+	wm.Unlock()
+	wm.fireChanged()
+	return wm
+}
+
+// SnapWindow moves and resizes w to occupy the given region of the manager.
+// The window's prior floated geometry is preserved so that Restore() can
+// un-snap it.
+func (wm *WindowManager) SnapWindow(w *Window, region SnapRegion) *WindowManager {
+	w.checkManager()
+	wm.Lock()
+	mx, my, mw, mh := wm.getInnerRect()
+	wm.Unlock()
+
+	if w.floating {
+		w.restoreX, w.restoreY, w.restoreHeight, w.restoreWidth = w.GetRect()
+	}
+
+	x, y, width, height := snapRegionRect(region, mx, my, mw, mh)
+	w.SetRect(x, y, width, height)
+	w.floating = false
+	w.snapRegion = region
+
+	wm.fireChanged()
+	return wm
+}
+
+// edgeSnapRegion returns the SnapRegion a window at the given rect should
+// preview, based on how close it is being dragged to a manager edge. It
+// returns SnapNone if the window isn't near any edge.
+func (wm *WindowManager) edgeSnapRegion(x, y, width, height int) SnapRegion {
+	mx, my, mw, mh := wm.getInnerRect()
+	nearLeft := x-mx <= snapEdgeThreshold
+	nearRight := (mx+mw)-(x+width) <= snapEdgeThreshold
+	nearTop := y-my <= snapEdgeThreshold
+	nearBottom := (my+mh)-(y+1) <= snapEdgeThreshold
+
+	switch {
+	case nearTop && nearLeft:
+		return SnapTopLeft
+	case nearTop && nearRight:
+		return SnapTopRight
+	case nearBottom && nearLeft:
+		return SnapBottomLeft
+	case nearBottom && nearRight:
+		return SnapBottomRight
+	case nearLeft:
+		return SnapLeft
+	case nearRight:
+		return SnapRight
+	case nearTop:
+		return SnapTop
+	case nearBottom:
+		return SnapBottom
+	default:
+		return SnapNone
+	}
+}
+
+// SetLayout selects how the manager arranges its windows. Switching to
+// LayoutTiled rebuilds the tile tree from the current window list.
+func (wm *WindowManager) SetLayout(layout Layout) *WindowManager {
+	wm.Lock()
+	defer wm.Unlock()
+	wm.layout = layout
+	if layout == LayoutTiled {
+		wm.rebuildTiles()
+	}
+	return wm
+}
+
+// GetLayout returns the manager's current layout mode.
+func (wm *WindowManager) GetLayout() Layout {
+	wm.Lock()
+	defer wm.Unlock()
+	return wm.layout
+}
+
+// Tiles returns a snapshot of the current tile tree, or nil if no windows
+// are tiled. Callers may walk it to serialize or inspect the layout.
+func (wm *WindowManager) Tiles() *TileNode {
+	wm.Lock()
+	defer wm.Unlock()
+	return exportTile(wm.tiles)
+}
+
+// SetChangedFunc sets a function which is called whenever a window is
+// shown, hidden, reordered, moved, or resized, so applications can persist
+// the layout (see SaveLayout) without tracking every mutation themselves.
+func (wm *WindowManager) SetChangedFunc(changed func()) *WindowManager {
+	wm.Lock()
+	defer wm.Unlock()
+	wm.changed = changed
+	return wm
+}
+
+// fireChanged invokes the function set via SetChangedFunc, if any. It must
+// not be called while wm's mutex is held.
+func (wm *WindowManager) fireChanged() {
+	wm.Lock()
+	changed := wm.changed
+	wm.Unlock()
+	if changed != nil {
+		changed()
+	}
+}
+
+// SetMouseCapture installs a function which runs before the manager
+// dispatches a mouse event to the window beneath the cursor, so
+// applications can implement app-wide gestures (e.g. middle-click-drag to
+// move any window, or right-click to raise). Returning a nil event cancels
+// propagation entirely.
+func (wm *WindowManager) SetMouseCapture(capture func(action MouseAction, event *tcell.EventMouse) (*tcell.EventMouse, MouseAction)) *WindowManager {
+	wm.Lock()
+	defer wm.Unlock()
+	wm.mouseCapture = capture
+	return wm
+}
+
+// GetMouseCapture returns the function installed with SetMouseCapture, or
+// nil if no such function has been installed.
+func (wm *WindowManager) GetMouseCapture() func(action MouseAction, event *tcell.EventMouse) (*tcell.EventMouse, MouseAction) {
+	wm.Lock()
+	defer wm.Unlock()
+	return wm.mouseCapture
+}
+
+// SetTaskbar attaches a WindowTaskbar to this manager. The taskbar's rect
+// is reserved from GetInnerRect and it is drawn after the windows.
+func (wm *WindowManager) SetTaskbar(taskbar *WindowTaskbar) *WindowManager {
+	wm.Lock()
+	defer wm.Unlock()
+	wm.taskbar = taskbar
+	if taskbar != nil {
+		taskbar.manager = wm
+	}
 	return wm
 }
 
+// GetTaskbar returns the taskbar installed with SetTaskbar, or nil if none
+// has been installed.
+func (wm *WindowManager) GetTaskbar() *WindowTaskbar {
+	wm.Lock()
+	defer wm.Unlock()
+	return wm.taskbar
+}
+
+// windowState is the JSON representation of one window's persisted layout.
+type windowState struct {
+	ID         string `json:"id"`
+	X          int    `json:"x"`
+	Y          int    `json:"y"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Z          int    `json:"z"`
+	Maximized  bool   `json:"maximized"`
+	Fullscreen bool   `json:"fullscreen"`
+	Visible    bool   `json:"visible"`
+}
+
+// SaveLayout serializes the position, size, z-order, and maximized/
+// fullscreen/visible flags of every window carrying an ID (see
+// Window.SetID) so a later LoadLayout call can restore the arrangement.
+func (wm *WindowManager) SaveLayout() ([]byte, error) {
+	wm.Lock()
+	states := make([]windowState, 0, len(wm.windows))
+	for z, window := range wm.windows {
+		if window.id == "" {
+			continue
+		}
+		x, y, width, height := window.GetRect()
+		states = append(states, windowState{
+			ID:         window.id,
+			X:          x,
+			Y:          y,
+			Width:      width,
+			Height:     height,
+			Z:          z,
+			Maximized:  window.maximized,
+			Fullscreen: window.fullscreen,
+			Visible:    window.visible,
+		})
+	}
+	wm.Unlock()
+	return json.Marshal(states)
+}
+
+// LoadLayout restores a layout previously produced by SaveLayout. resolve
+// maps each persisted window ID to the *Window the caller has already
+// constructed for it; entries for which resolve returns nil are skipped.
+func (wm *WindowManager) LoadLayout(data []byte, resolve func(id string) *Window) error {
+	var states []windowState
+	if err := json.Unmarshal(data, &states); err != nil {
+		return err
+	}
+
+	for _, state := range states {
+		window := resolve(state.ID)
+		if window == nil {
+			continue
+		}
+
+		window.SetID(state.ID)
+		wm.Show(window)
+
+		window.restoreX, window.restoreY, window.restoreHeight, window.restoreWidth = state.X, state.Y, state.Width, state.Height
+		window.SetRect(window.restoreRect())
+		window.floating = true
+
+		wm.Lock()
+		wm.setZ(window, state.Z)
+		wm.Unlock()
+
+		if state.Maximized {
+			window.Maximize()
+		}
+		if state.Fullscreen {
+			window.SetFullscreen(true)
+		}
+		window.SetVisible(state.Visible)
+	}
+
+	wm.fireChanged()
+	return nil
+}
+
+// rebuildTiles discards the current tile tree and re-tiles every window in
+// the manager, in z-order.
+func (wm *WindowManager) rebuildTiles() {
+	wm.tiles = nil
+	for _, window := range wm.windows {
+		wm.insertTile(window)
+	}
+}
+
+// insertTile adds window to the tile tree, splitting whichever existing
+// leaf currently occupies the largest area.
+func (wm *WindowManager) insertTile(window *Window) {
+	if window.floating {
+		window.restoreX, window.restoreY, window.restoreHeight, window.restoreWidth = window.GetRect()
+	}
+	window.floating = false
+	window.snapRegion = SnapNone
+
+	if wm.tiles == nil {
+		wm.tiles = &tileNode{window: window}
+		return
+	}
+
+	mx, my, mw, mh := wm.getInnerRect()
+	leaf, lx, ly, lw, lh := largestTileLeaf(wm.tiles, mx, my, mw, mh)
+	_, _ = lx, ly
+	leaf.horizontal = lw >= lh
+	leaf.ratio = 0.5
+	leaf.first = &tileNode{window: leaf.window}
+	leaf.second = &tileNode{window: window}
+	leaf.window = nil
+}
+
+// removeTile removes window's leaf from the tile tree, collapsing its
+// sibling into the vacated parent slot.
+func (wm *WindowManager) removeTile(window *Window) {
+	wm.tiles = removeTileNode(wm.tiles, window)
+}
+
+func removeTileNode(node *tileNode, window *Window) *tileNode {
+	if node == nil {
+		return nil
+	}
+	if node.first == nil {
+		if node.window == window {
+			return nil
+		}
+		return node
+	}
+	node.first = removeTileNode(node.first, window)
+	node.second = removeTileNode(node.second, window)
+	if node.first == nil {
+		return node.second
+	}
+	if node.second == nil {
+		return node.first
+	}
+	return node
+}
+
+// largestTileLeaf walks the tile tree, computing each leaf's rect as it
+// goes, and returns the leaf with the greatest area along with its rect.
+func largestTileLeaf(node *tileNode, x, y, width, height int) (leaf *tileNode, lx, ly, lw, lh int) {
+	if node.first == nil {
+		return node, x, y, width, height
+	}
+
+	fx, fy, fw, fh := x, y, width, height
+	sx, sy, sw, sh := x, y, width, height
+	if node.horizontal {
+		fw = int(float64(width) * node.ratio)
+		sx, sw = x+fw, width-fw
+	} else {
+		fh = int(float64(height) * node.ratio)
+		sy, sh = y+fh, height-fh
+	}
+
+	fLeaf, flx, fly, flw, flh := largestTileLeaf(node.first, fx, fy, fw, fh)
+	sLeaf, slx, sly, slw, slh := largestTileLeaf(node.second, sx, sy, sw, sh)
+	if flw*flh >= slw*slh {
+		return fLeaf, flx, fly, flw, flh
+	}
+	return sLeaf, slx, sly, slw, slh
+}
+
+// arrangeTiles applies the tile tree's computed rects to each tiled
+// window's geometry.
+func (wm *WindowManager) arrangeTiles(x, y, width, height int) {
+	applyTileRects(wm.tiles, x, y, width, height)
+}
+
+func applyTileRects(node *tileNode, x, y, width, height int) {
+	if node == nil {
+		return
+	}
+	if node.first == nil {
+		if node.window != nil {
+			node.window.SetRect(x, y, width, height)
+		}
+		return
+	}
+
+	fx, fy, fw, fh := x, y, width, height
+	sx, sy, sw, sh := x, y, width, height
+	if node.horizontal {
+		fw = int(float64(width) * node.ratio)
+		sx, sw = x+fw, width-fw
+	} else {
+		fh = int(float64(height) * node.ratio)
+		sy, sh = y+fh, height-fh
+	}
+
+	applyTileRects(node.first, fx, fy, fw, fh)
+	applyTileRects(node.second, sx, sy, sw, sh)
+}
+
+// drawSnapPreview paints a translucent highlight over the region a
+// currently-dragged window would snap to.
+func (wm *WindowManager) drawSnapPreview(screen tcell.Screen, region SnapRegion) {
+	mx, my, mw, mh := wm.getInnerRect()
+	x, y, width, height := snapRegionRect(region, mx, my, mw, mh)
+	for py := y; py < y+height; py++ {
+		for px := x; px < x+width; px++ {
+			mainc, combc, style, _ := screen.GetContent(px, py)
+			style = style.Background(tcell.ColorBlue).Dim(true)
+			screen.SetContent(px, py, mainc, combc, style)
+		}
+	}
+}
+
 // Draw draws this primitive onto the screen.
 func (wm *WindowManager) Draw(screen tcell.Screen) {
 	wm.Box.Draw(screen)
 
 	wm.Lock()
-	defer wm.Unlock()
 
 	lenW := len(wm.windows)
 	if lenW > 1 {
 		for i, window := range wm.windows {
-			if window.HasFocus() && i != lenW-1 {
+			if window.visible && window.HasFocus() && i != lenW-1 {
 				wm.setZ(window, WindowZTop)
 				break
 			}
 		}
 	}
 
-	for _, window := range wm.windows {
-		mx, my, mw, mh := wm.GetInnerRect()
-		x, y, w, h := window.GetRect()
-		if x < mx {
-			x = mx
-		}
-		if y < my {
-			y = my
+	for i := len(wm.windows) - 1; i >= 0; i-- {
+		window := wm.windows[i]
+		if window.visible && window.fullscreen {
+			mx, my, mw, mh := wm.getInnerRect()
+			window.drawFullscreen(screen, mx, my, mw, mh)
+			taskbar := wm.taskbar
+			wm.Unlock()
+			if taskbar != nil {
+				taskbar.Draw(screen)
+			}
+			return
 		}
+	}
 
-		if w < minWindowWidth {
-			w = minWindowWidth
-		}
-		if h < minWindowHeight {
-			h = minWindowHeight
-		}
+	if wm.layout == LayoutTiled {
+		mx, my, mw, mh := wm.getInnerRect()
+		wm.arrangeTiles(mx, my, mw, mh)
+	}
 
-		if w > mw || window.maximized {
-			w = mw
-			x = mx
-		}
-		if h > mh || window.maximized {
-			h = mh
-			y = my
+	for _, window := range wm.windows {
+		if !window.visible {
+			continue
 		}
 
-		if x+w > mx+mw {
-			x = mx + mw - w
-		}
+		mx, my, mw, mh := wm.getInnerRect()
+		x, y, w, h := window.GetRect()
+		if window.floating {
+			if x < mx {
+				x = mx
+			}
+			if y < my {
+				y = my
+			}
 
-		if y+h > my+mh {
-			y = my + mh - h
-		}
+			if w < minWindowWidth {
+				w = minWindowWidth
+			}
+
+			if w > mw || window.maximized {
+				w = mw
+				x = mx
+			}
+			if window.shaded {
+				h = 1
+			} else {
+				if h < minWindowHeight {
+					h = minWindowHeight
+				}
+				if h > mh || window.maximized {
+					h = mh
+					y = my
+				}
+			}
+
+			if x+w > mx+mw {
+				x = mx + mw - w
+			}
 
-		window.SetRect(x, y, w, h)
+			if y+h > my+mh {
+				y = my + mh - h
+			}
+
+			window.SetRect(x, y, w, h)
+		}
 		window.Draw(screen)
 	}
+
+	if wm.snapPreview != SnapNone && wm.draggedWindow != nil {
+		wm.drawSnapPreview(screen, wm.snapPreview)
+	}
+
+	taskbar := wm.taskbar
+	wm.Unlock()
+
+	if taskbar != nil {
+		taskbar.Draw(screen)
+	}
 }
 
 // Focus is called when this primitive receives focus.
@@ -463,6 +1651,32 @@ func (wm *WindowManager) SetRect(x, y, width, height int) {
 	}
 }
 
+// getInnerRect is the lock-free core of GetInnerRect, for callers that
+// already hold wm's lock.
+func (wm *WindowManager) getInnerRect() (int, int, int, int) {
+	x, y, width, height := wm.Box.GetInnerRect()
+	taskbar := wm.taskbar
+	if taskbar == nil || height <= taskbarHeight {
+		return x, y, width, height
+	}
+
+	if taskbar.edge == TaskbarTop {
+		taskbar.SetRect(x, y, width, taskbarHeight)
+		return x, y + taskbarHeight, width, height - taskbarHeight
+	}
+
+	taskbar.SetRect(x, y+height-taskbarHeight, width, taskbarHeight)
+	return x, y, width, height - taskbarHeight
+}
+
+// GetInnerRect returns the inner rectangle windows are confined to,
+// excluding the space reserved for an attached taskbar, if any.
+func (wm *WindowManager) GetInnerRect() (int, int, int, int) {
+	wm.Lock()
+	defer wm.Unlock()
+	return wm.getInnerRect()
+}
+
 // HasFocus returns whether or not this primitive has focus.
 func (wm *WindowManager) HasFocus() bool {
 	wm.Lock()
@@ -483,17 +1697,58 @@ func (wm *WindowManager) MouseHandler() func(action MouseAction, event *tcell.Ev
 		if !wm.InRect(event.Position()) {
 			return false, nil
 		}
+
 		wm.Lock()
+		taskbar := wm.taskbar
+		wm.Unlock()
+
+		if taskbar != nil && taskbar.InRect(event.Position()) {
+			return taskbar.MouseHandler()(action, event, setFocus)
+		}
+
+		wm.Lock()
+		mouseCapture := wm.mouseCapture
+		wm.Unlock()
+
+		if mouseCapture != nil {
+			originalAction := action
+			var capturedEvent *tcell.EventMouse
+			capturedEvent, action = mouseCapture(action, event)
+			if capturedEvent == nil {
+				if originalAction == MouseLeftUp {
+					wm.Lock()
+					wm.draggedWindow = nil
+					wm.snapPreview = SnapNone
+					wm.Unlock()
+				}
+				return true, nil
+			}
+			event = capturedEvent
+		}
+
+		wm.Lock()
+		fireAfterUnlock := false
 
 		if wm.draggedWindow != nil {
 			switch action {
 			case MouseLeftUp:
+				window := wm.draggedWindow
+				region := wm.snapPreview
 				wm.draggedWindow = nil
+				wm.snapPreview = SnapNone
+				if region != SnapNone {
+					wm.Unlock()
+					wm.SnapWindow(window, region)
+					return true, nil
+				}
+				fireAfterUnlock = true
 			case MouseMove:
 				x, y := event.Position()
 				wx, wy, ww, wh := wm.draggedWindow.GetRect()
 				if wm.draggedEdge == WindowEdgeTop && wm.draggedWindow.Draggable {
-					wm.draggedWindow.SetRect(x-wm.dragOffsetX, y-wm.dragOffsetY, ww, wh)
+					nx, ny := x-wm.dragOffsetX, y-wm.dragOffsetY
+					wm.draggedWindow.SetRect(nx, ny, ww, wh)
+					wm.snapPreview = wm.edgeSnapRegion(nx, ny, ww, wh)
 				} else {
 					if wm.draggedWindow.Resizable {
 						switch wm.draggedEdge {
@@ -525,7 +1780,7 @@ func (wm *WindowManager) MouseHandler() func(action MouseAction, event *tcell.Ev
 		// Pass mouse events along to the first child item that takes it.
 		for i := len(windows) - 1; i >= 0; i-- {
 			window := windows[i]
-			if !window.InRect(event.Position()) {
+			if !window.visible || !window.InRect(event.Position()) {
 				continue
 			}
 
@@ -537,6 +1792,10 @@ func (wm *WindowManager) MouseHandler() func(action MouseAction, event *tcell.Ev
 				x, y := event.Position()
 				wm.draggedEdge = WindowEdgeNone
 				switch {
+				case window.shaded:
+					// A shaded window is only its title row; treat the
+					// whole thing as draggable rather than resizable.
+					wm.draggedEdge = WindowEdgeTop
 				case y == wy+wh-1:
 					switch {
 					case x == wx:
@@ -562,9 +1821,15 @@ func (wm *WindowManager) MouseHandler() func(action MouseAction, event *tcell.Ev
 				}
 			}
 			wm.Unlock()
+			if fireAfterUnlock {
+				wm.fireChanged()
+			}
 			return window.MouseHandler()(action, event, setFocus)
 		}
 		wm.Unlock()
+		if fireAfterUnlock {
+			wm.fireChanged()
+		}
 
 		return
 	})